@@ -0,0 +1,129 @@
+// Package config implements a versioned, self-migrating schema for hauler's
+// driver config files. It is modeled on k3d's v1alpha2->v1alpha3 config
+// migration: a registry of JSON schemas keyed by apiVersion, looked up at
+// load time from the file's own apiVersion field, validated before use, and
+// carried forward to CurrentApiVersion via per-version migration functions.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/xeipuuv/gojsonschema"
+	"sigs.k8s.io/yaml"
+)
+
+// apiVersionProbe is used to sniff a config file's apiVersion before picking
+// a schema to validate the rest of it against
+type apiVersionProbe struct {
+	ApiVersion string `json:"apiVersion"`
+}
+
+// SchemaFor returns the JSON schema registered for apiVersion
+func SchemaFor(apiVersion string) ([]byte, error) {
+	s, ok := schemas[apiVersion]
+	if !ok {
+		return nil, fmt.Errorf("no config schema registered for apiVersion %q", apiVersion)
+	}
+	return s, nil
+}
+
+// Validate checks a raw config.yaml document against the JSON schema
+// registered for its apiVersion
+func Validate(data []byte) error {
+	var probe apiVersionProbe
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return err
+	}
+
+	apiVersion := probe.ApiVersion
+	if apiVersion == "" {
+		apiVersion = defaultApiVersion
+	}
+
+	schema, err := SchemaFor(apiVersion)
+	if err != nil {
+		return err
+	}
+
+	// A config with no apiVersion is validated as defaultApiVersion, so fill
+	// that in before checking it against the schema's required apiVersion
+	if probe.ApiVersion == "" {
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		if doc == nil {
+			doc = map[string]interface{}{}
+		}
+		doc["apiVersion"] = apiVersion
+		if data, err = yaml.Marshal(doc); err != nil {
+			return err
+		}
+	}
+
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return err
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(jsonData))
+	if err != nil {
+		return err
+	}
+	if !result.Valid() {
+		msg := fmt.Sprintf("config failed validation for apiVersion %s:", apiVersion)
+		for _, e := range result.Errors() {
+			msg += fmt.Sprintf("\n  - %s", e)
+		}
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// Migrate walks cfg forward through the registered migrations until it
+// reaches CurrentApiVersion
+func Migrate(cfg map[string]interface{}) (map[string]interface{}, error) {
+	for {
+		av, _ := cfg["apiVersion"].(string)
+		if av == "" {
+			av = defaultApiVersion
+		}
+		if av == CurrentApiVersion {
+			cfg["apiVersion"] = av
+			return cfg, nil
+		}
+
+		fn, ok := migrations[av]
+		if !ok {
+			return nil, fmt.Errorf("no migration path from apiVersion %q to %s", av, CurrentApiVersion)
+		}
+
+		migrated, err := fn(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("migrating config from %s: %w", av, err)
+		}
+		cfg = migrated
+	}
+}
+
+// ReadFile loads the config.yaml at path, validates it against its declared
+// apiVersion's schema, and migrates it to CurrentApiVersion
+func ReadFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Validate(data); err != nil {
+		return nil, err
+	}
+
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	return Migrate(cfg)
+}
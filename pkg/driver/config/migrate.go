@@ -0,0 +1,49 @@
+package config
+
+import (
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// MigrateFile rewrites the config.yaml at path to CurrentApiVersion in
+// place, backing the `config migrate` command. It reports whether the file
+// needed any changes.
+func MigrateFile(path string) (migrated bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var probe apiVersionProbe
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return false, err
+	}
+	if probe.ApiVersion == CurrentApiVersion {
+		return false, nil
+	}
+
+	if err := Validate(data); err != nil {
+		return false, err
+	}
+
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return false, err
+	}
+
+	cfg, err = Migrate(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
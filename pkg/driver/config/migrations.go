@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/rancherfederal/hauler/pkg/driver/config/v1alpha1"
+	"github.com/rancherfederal/hauler/pkg/driver/config/v1alpha2"
+)
+
+// CurrentApiVersion is the apiVersion new config files are written at
+const CurrentApiVersion = v1alpha2.ApiVersion
+
+// defaultApiVersion is assumed for a config with no apiVersion field at all,
+// e.g. a plain k3s config.yaml predating hauler's versioned schema
+const defaultApiVersion = v1alpha1.ApiVersion
+
+// schemas maps an apiVersion to the JSON schema used to validate configs at
+// that version
+var schemas = map[string][]byte{
+	v1alpha1.ApiVersion: v1alpha1.JSONSchema,
+	v1alpha2.ApiVersion: v1alpha2.JSONSchema,
+}
+
+// migrationFunc transforms a parsed config one apiVersion forward
+type migrationFunc func(map[string]interface{}) (map[string]interface{}, error)
+
+// migrations maps an apiVersion to the function that migrates a config at
+// that version forward to the next one. Migrate applies these repeatedly
+// until a config reaches CurrentApiVersion.
+var migrations = map[string]migrationFunc{
+	v1alpha1.ApiVersion: migrateV1Alpha1ToV1Alpha2,
+}
+
+// migrateV1Alpha1ToV1Alpha2 carries v1alpha1 fields forward unchanged; there
+// are no renames, only the addition of write-kubeconfig-group, which callers
+// pick up by simply leaving it unset.
+func migrateV1Alpha1ToV1Alpha2(cfg map[string]interface{}) (map[string]interface{}, error) {
+	cfg["apiVersion"] = v1alpha2.ApiVersion
+	return cfg, nil
+}
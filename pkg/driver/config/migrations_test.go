@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/rancherfederal/hauler/pkg/driver/config/v1alpha1"
+	"github.com/rancherfederal/hauler/pkg/driver/config/v1alpha2"
+)
+
+func TestMigrateV1Alpha1ToV1Alpha2(t *testing.T) {
+	cfg := map[string]interface{}{
+		"apiVersion": v1alpha1.ApiVersion,
+		"data-dir":   "/var/lib/rancher/k3s",
+		"disable":    []interface{}{"traefik"},
+	}
+
+	migrated, err := Migrate(cfg)
+	if err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+
+	if migrated["apiVersion"] != v1alpha2.ApiVersion {
+		t.Errorf("apiVersion = %v, want %v", migrated["apiVersion"], v1alpha2.ApiVersion)
+	}
+	if migrated["data-dir"] != "/var/lib/rancher/k3s" {
+		t.Errorf("data-dir was not carried forward: %v", migrated["data-dir"])
+	}
+}
+
+func TestMigrateUnknownApiVersion(t *testing.T) {
+	_, err := Migrate(map[string]interface{}{"apiVersion": "hauler.cattle.io/v0"})
+	if err == nil {
+		t.Fatal("Migrate() with no registered migration path should return an error")
+	}
+}
+
+func TestMigrateAlreadyCurrent(t *testing.T) {
+	cfg := map[string]interface{}{"apiVersion": CurrentApiVersion}
+
+	migrated, err := Migrate(cfg)
+	if err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	if migrated["apiVersion"] != CurrentApiVersion {
+		t.Errorf("apiVersion = %v, want %v", migrated["apiVersion"], CurrentApiVersion)
+	}
+}
@@ -0,0 +1,23 @@
+// Package v1alpha1 is the original hauler driver config schema. It is kept
+// around solely so older config.yaml files can be validated and migrated
+// forward; new configs should target the latest apiVersion instead.
+package v1alpha1
+
+import _ "embed"
+
+// ApiVersion is the apiVersion string that selects this schema
+const ApiVersion = "hauler.cattle.io/v1alpha1"
+
+// K3sConfig is the v1alpha1 shape of the k3s driver config file
+type K3sConfig struct {
+	ApiVersion string `json:"apiVersion"`
+
+	DataDir        string `json:"data-dir,omitempty"`
+	KubeConfig     string `json:"write-kubeconfig,omitempty"`
+	KubeConfigMode string `json:"write-kubeconfig-mode,omitempty"`
+
+	Disable []string `json:"disable,omitempty"`
+}
+
+//go:embed schema.json
+var JSONSchema []byte
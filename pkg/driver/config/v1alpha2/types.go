@@ -0,0 +1,23 @@
+// Package v1alpha2 is the current hauler driver config schema.
+package v1alpha2
+
+import _ "embed"
+
+// ApiVersion is the apiVersion string that selects this schema
+const ApiVersion = "hauler.cattle.io/v1alpha2"
+
+// K3sConfig is the v1alpha2 shape of the k3s driver config file. It adds
+// write-kubeconfig-group over v1alpha1.
+type K3sConfig struct {
+	ApiVersion string `json:"apiVersion"`
+
+	DataDir         string `json:"data-dir,omitempty"`
+	KubeConfig      string `json:"write-kubeconfig,omitempty"`
+	KubeConfigMode  string `json:"write-kubeconfig-mode,omitempty"`
+	KubeConfigGroup string `json:"write-kubeconfig-group,omitempty"`
+
+	Disable []string `json:"disable,omitempty"`
+}
+
+//go:embed schema.json
+var JSONSchema []byte
@@ -0,0 +1,66 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// Driver abstracts a single-node Kubernetes distribution that hauler can bootstrap
+// in an air-gapped environment. K3s, RKE2, and K0s each implement this interface
+// against their own release-artifact layout and install mechanism.
+type Driver interface {
+	// Name returns the short, lowercase identifier for the driver (e.g. "k3s")
+	Name() string
+
+	// KubeConfigPath returns the path the driver will write its kubeconfig to
+	KubeConfigPath() string
+
+	// DataPath joins elem onto the driver's data directory
+	DataPath(elem ...string) string
+
+	// WriteConfig merges the driver's configuration into its on-disk config file
+	WriteConfig() error
+
+	// Images returns the air-gap image set required to run the driver, resolved
+	// against a remote registry
+	Images(ctx context.Context) (map[name.Reference]v1.Image, error)
+
+	// Binary returns a reader over the driver's release binary (or archive)
+	Binary() (io.ReadCloser, error)
+
+	// SystemObjects returns the objects hauler will wait on to be Ready before
+	// accepting new resources
+	SystemObjects() []object.ObjMetadata
+
+	// Start installs and starts the driver, streaming install output to out
+	Start(out io.Writer) error
+
+	// Probe connects to the running cluster, waits for SystemObjects to
+	// reach Ready, and returns a structured diagnostic report
+	Probe(ctx context.Context) (*ProbeResult, error)
+}
+
+// New returns the Driver registered under name for the given release version
+func New(driverName string, version string) (Driver, error) {
+	switch driverName {
+	case "k3s", "":
+		k := NewK3s()
+		k.Version = version
+		return k, nil
+	case "rke2":
+		r := NewRKE2()
+		r.Version = version
+		return r, nil
+	case "k0s":
+		k := NewK0s()
+		k.Version = version
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unknown driver %q", driverName)
+	}
+}
@@ -0,0 +1,124 @@
+package driver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// InstallSpec carries everything an Installer needs to stand up a driver's
+// service. Binary placement is the caller's responsibility (hauler's
+// packager writes the fetched Binary() into BinDir); Installer only wires
+// the service around it.
+type InstallSpec struct {
+	// Name is the service name, e.g. "k3s"
+	Name string
+	// BinDir is the directory the driver binary already lives in
+	BinDir string
+	// ImagesDir, if set, is where the driver's airgap image bundle belongs.
+	// NativeInstaller only ensures the directory exists today; it doesn't
+	// populate it, so a native install still needs the bundle staged there
+	// by some other means before the service starts.
+	// TODO: have NativeInstaller actually seed ImagesDir once there's a
+	// driver-agnostic way to write a resolved image set to disk.
+	ImagesDir string
+	// Script is the embedded install script a ScriptInstaller runs
+	Script string
+	// Env holds install-time environment variables for ScriptInstaller's
+	// install script (e.g. INSTALL_K3S_SKIP_DOWNLOAD). They're meaningless
+	// to the long-running service a NativeInstaller unit starts, so only
+	// ScriptInstaller consumes Env.
+	Env []string
+}
+
+// Installer performs the OS-level steps to install and start a driver as a
+// service. K3s.Start delegates to one so the mechanism is swappable: the
+// original shell-script installer, a native Go installer for hosts where
+// shelling out isn't desirable, and a dry-run installer for previewing
+// changes or driving unit tests with a fake.
+type Installer interface {
+	Install(spec InstallSpec, out io.Writer) error
+}
+
+// ScriptInstaller writes spec.Script to BinDir and runs it through
+// /bin/sh, exactly as Start always has. It's the default Installer for K3s.
+type ScriptInstaller struct{}
+
+func (ScriptInstaller) Install(spec InstallSpec, out io.Writer) error {
+	scriptPath := filepath.Join(spec.BinDir, spec.Name+"-init.sh")
+	if err := os.WriteFile(scriptPath, []byte(spec.Script), 0755); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("/bin/sh", scriptPath)
+	cmd.Env = append(os.Environ(), spec.Env...)
+	cmd.Stdout = out
+	return cmd.Run()
+}
+
+// NativeInstaller installs the driver directly, without shelling out to the
+// upstream install script: it writes a systemd unit that invokes the binary
+// already in spec.BinDir, ensures spec.ImagesDir exists, and enables+starts
+// it. It deliberately ignores spec.Env: those are install-script variables,
+// not runtime environment for the service.
+type NativeInstaller struct{}
+
+func (NativeInstaller) Install(spec InstallSpec, out io.Writer) error {
+	if spec.ImagesDir != "" {
+		if err := os.MkdirAll(spec.ImagesDir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=%[1]s
+After=network-online.target
+
+[Service]
+Type=notify
+ExecStart=%[2]s/%[1]s server
+KillMode=process
+Delegate=yes
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+`, spec.Name, spec.BinDir)
+
+	unitPath := fmt.Sprintf("/etc/systemd/system/%s.service", spec.Name)
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"daemon-reload"},
+		{"enable", "--now", spec.Name},
+	} {
+		cmd := exec.Command("systemctl", args...)
+		cmd.Stdout = out
+		cmd.Stderr = out
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("systemctl %v: %w", args, err)
+		}
+	}
+	return nil
+}
+
+// DryRunInstaller performs no host changes; it writes the plan it would
+// have executed to out instead. This is the real INSTALL_K3S_SKIP_START
+// dry-run, and lets Start be unit-tested with nothing touching the host.
+type DryRunInstaller struct{}
+
+func (DryRunInstaller) Install(spec InstallSpec, out io.Writer) error {
+	fmt.Fprintf(out, "[dryrun] would install %s from %s\n", spec.Name, spec.BinDir)
+	if spec.ImagesDir != "" {
+		fmt.Fprintf(out, "[dryrun]   seed images into %s\n", spec.ImagesDir)
+	}
+	for _, e := range spec.Env {
+		fmt.Fprintf(out, "[dryrun]   env: %s\n", e)
+	}
+	fmt.Fprintf(out, "[dryrun] would enable and start %s\n", spec.Name)
+	return nil
+}
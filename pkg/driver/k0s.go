@@ -0,0 +1,217 @@
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/imdario/mergo"
+	"github.com/rancherfederal/hauler/pkg/packager/images"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	k0sReleaseUrl = "https://github.com/k0sproject/k0s/releases/download"
+)
+
+// K0s is the Driver implementation for k0sproject/k0s
+type K0s struct {
+	Version string
+	Arch    string
+
+	Config K0sConfig
+}
+
+var _ Driver = K0s{}
+
+//TODO: Would be nice if these just pointed to k0s/pkg/config
+type K0sConfig struct {
+	DataDir    string `json:"dataDir,omitempty"`
+	KubeConfig string `json:"-"`
+}
+
+//NewK0s returns a new k0s driver
+func NewK0s() K0s {
+	//TODO: Allow for configuration overrides
+	return K0s{
+		Arch: runtime.GOARCH,
+		Config: K0sConfig{
+			DataDir:    "/var/lib/k0s",
+			KubeConfig: "/var/lib/k0s/pki/admin.conf",
+		},
+	}
+}
+
+func (k K0s) Name() string { return "k0s" }
+
+func (k K0s) KubeConfigPath() string { return k.Config.KubeConfig }
+
+func (k K0s) DataPath(elem ...string) string {
+	base := []string{k.Config.DataDir}
+	return filepath.Join(append(base, elem...)...)
+}
+
+// WriteConfig merges k0s's k0sVars into k0s.yaml; unlike k3s/rke2, k0s's config is
+// a full Kubernetes-style manifest rather than a flat flag map. WriteConfig
+// writes k.Config's fields as flat top-level keys (e.g. dataDir), the same way
+// RKE2.WriteConfig does; it does not wrap them in an apiVersion/kind/spec manifest.
+func (k K0s) WriteConfig() error {
+	cfgPath := "/etc/k0s"
+	if err := os.MkdirAll(cfgPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(k.Config)
+	if err != nil {
+		return err
+	}
+
+	c := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return err
+	}
+
+	var uc map[string]interface{}
+	path := filepath.Join(cfgPath, "k0s.yaml")
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &uc); err != nil {
+			return err
+		}
+	}
+
+	//Merge with user defined configs taking precedence
+	if err := mergo.Merge(&c, uc, mergo.WithOverride); err != nil {
+		return err
+	}
+
+	mergedData, err := yaml.Marshal(&c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, mergedData, 0644)
+}
+
+func (k K0s) Images(ctx context.Context) (map[name.Reference]v1.Image, error) {
+	imgs, err := k.listImages()
+	if err != nil {
+		return nil, err
+	}
+	return images.ResolveRemoteRefs(imgs...)
+}
+
+// Binary returns k0s's single static binary for k.Arch
+func (k K0s) Binary() (io.ReadCloser, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/v%s/k0s-v%s-%s", k0sReleaseUrl, k.Version, k.Version, k.Arch))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(u.String())
+	if err != nil || resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to return executable for k0s %s from %s", k.Version, u.String())
+	}
+	return resp.Body, nil
+}
+
+//SystemObjects returns a slice of object.ObjMetadata required for driver to be functional and accept new resources
+//hauler's bootstrapping sequence will always wait for SystemObjects to be in a Ready status before proceeding
+func (k K0s) SystemObjects() (objs []object.ObjMetadata) {
+	for _, dep := range []string{"coredns"} {
+		objMeta, _ := object.CreateObjMetadata("kube-system", dep, schema.GroupKind{Kind: "Deployment", Group: "apps"})
+		objs = append(objs, objMeta)
+	}
+	return objs
+}
+
+func (k K0s) Start(out io.Writer) error {
+	if err := os.MkdirAll("/opt/hauler/bin", os.ModePerm); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("/opt/hauler/bin/k0s", "install", "controller", "--single")
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	cmd = exec.Command("/opt/hauler/bin/k0s", "start")
+	cmd.Stdout = out
+	return cmd.Run()
+}
+
+// Probe waits for k's SystemObjects to become Ready and reports a
+// structured diagnostic summary of the running cluster
+func (k K0s) Probe(ctx context.Context) (*ProbeResult, error) {
+	return probe(ctx, k, defaultProbeTimeout)
+}
+
+// listImages fetches k0s's airgap image bundle list for the target version; k0s
+// ships this as `k0s airgap list-images` output rather than a static release asset,
+// so listImages runs that command against its own cached copy of the k0s binary
+// rather than requiring one already installed at /opt/hauler/bin/k0s — the host
+// building an airgap bundle via Images() won't have k0s installed yet.
+func (k K0s) listImages() ([]string, error) {
+	binPath, err := k.cachedBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images for k0s %s: %w", k.Version, err)
+	}
+
+	cmd := exec.Command(binPath, "airgap", "list-images")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images for k0s %s: %w", k.Version, err)
+	}
+
+	var imgs []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		imgs = append(imgs, scanner.Text())
+	}
+
+	return imgs, nil
+}
+
+// cachedBinary returns the path to a locally cached copy of k.Binary(),
+// downloading it first if it isn't already cached
+func (k K0s) cachedBinary() (string, error) {
+	cacheDir := k.DataPath("cache", "bin")
+	path := filepath.Join(cacheDir, fmt.Sprintf("k0s-%s-%s", k.Version, k.Arch))
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	rc, err := k.Binary()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return "", err
+	}
+	return path, nil
+}
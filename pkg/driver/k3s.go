@@ -2,20 +2,20 @@ package driver
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	_ "embed"
 	"fmt"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/imdario/mergo"
+	"github.com/rancherfederal/hauler/pkg/driver/config"
 	"github.com/rancherfederal/hauler/pkg/packager/images"
 	"io"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"net/http"
-	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sigs.k8s.io/cli-utils/pkg/object"
 	"sigs.k8s.io/yaml"
 )
@@ -27,26 +27,51 @@ const (
 //go:embed embed/k3s-init.sh
 var k3sInit string
 
+// K3s is the Driver implementation for k3s-io/k3s
 type K3s struct {
 	Version string
 
+	// Arch selects the release artifact arch (amd64, arm64, arm, s390x).
+	// Defaults to amd64 when empty.
+	Arch string
+
 	Config K3sConfig
+
+	// Installer performs Start's OS-level install steps. Defaults to
+	// ScriptInstaller when nil; inject DryRunInstaller or a fake for tests.
+	Installer Installer
 }
 
+var _ Driver = K3s{}
+
 //TODO: Would be nice if these just pointed to k3s/pkg/cli/cmds
 type K3sConfig struct {
-	DataDir        string `json:"data-dir,omitempty"`
-	KubeConfig     string `json:"write-kubeconfig,omitempty"`
-	KubeConfigMode string `json:"write-kubeconfig-mode,omitempty"`
+	// ApiVersion tracks which hauler schema this config was authored against.
+	// It's hauler's own bookkeeping, not a k3s server flag, so it's never
+	// marshaled into the config.yaml k3s itself reads.
+	ApiVersion string `json:"-"`
+
+	DataDir         string `json:"data-dir,omitempty"`
+	KubeConfig      string `json:"write-kubeconfig,omitempty"`
+	KubeConfigMode  string `json:"write-kubeconfig-mode,omitempty"`
+	KubeConfigGroup string `json:"write-kubeconfig-group,omitempty"`
 
 	Disable []string `json:"disable,omitempty"`
+
+	// ExtraArgs is an escape hatch for k3s server flags hauler doesn't model
+	// directly, keyed by flag name (without the leading --) to its value(s).
+	// It mirrors k3d's --k3s-arg: flags that can repeat take multiple values
+	// and are written to config.yaml as a YAML list.
+	ExtraArgs map[string][]string `json:"-"`
 }
 
 //NewK3s returns a new k3s driver
 func NewK3s() K3s {
 	//TODO: Allow for configuration overrides
 	return K3s{
+		Arch: runtime.GOARCH,
 		Config: K3sConfig{
+			ApiVersion:     config.CurrentApiVersion,
 			DataDir:        "/var/lib/rancher/k3s",
 			KubeConfig:     "/etc/rancher/k3s/k3s.yaml",
 			KubeConfigMode: "0644",
@@ -70,25 +95,54 @@ func (k K3s) WriteConfig() error {
 		return err
 	}
 
+	if k.Config.ApiVersion == "" {
+		k.Config.ApiVersion = config.CurrentApiVersion
+	}
+
 	data, err := yaml.Marshal(k.Config)
+	if err != nil {
+		return err
+	}
 
 	c := make(map[string]interface{})
 	if err := yaml.Unmarshal(data, &c); err != nil {
 		return err
 	}
 
-	var uc map[string]interface{}
+	// ExtraArgs aren't part of the typed K3sConfig shape, so fold them into
+	// the merged map directly. k3s only accepts a YAML list for flags that
+	// can repeat, so a single value is written as a scalar and a no-value
+	// flag (a boolean switch) is written as true; only 2+ values become a list.
+	for flag, values := range k.Config.ExtraArgs {
+		switch len(values) {
+		case 0:
+			c[flag] = true
+		case 1:
+			c[flag] = values[0]
+		default:
+			c[flag] = values
+		}
+	}
+
+	// If a config.yaml already exists (e.g. a plain k3s config with no
+	// apiVersion at all, or one from a previous, possibly older hauler
+	// release), validate it against its declared apiVersion's schema and
+	// migrate it to config.CurrentApiVersion before merging.
 	path := filepath.Join(kCfgPath, "config.yaml")
-	if data, err := os.ReadFile(path); err != nil {
-		err := yaml.Unmarshal(data, &uc)
+	if _, err := os.Stat(path); err == nil {
+		uc, err := config.ReadFile(path)
 		if err != nil {
-			return err
+			return fmt.Errorf("reading existing config %s: %w", path, err)
 		}
-	}
 
-	//Merge with user defined configs taking precedence
-	if err := mergo.Merge(&c, uc); err != nil {
-		return err
+		// apiVersion is hauler's own bookkeeping; strip it back out so it
+		// never ends up in the config.yaml k3s itself reads.
+		delete(uc, "apiVersion")
+
+		//Merge with user defined configs taking precedence
+		if err := mergo.Merge(&c, uc, mergo.WithOverride); err != nil {
+			return err
+		}
 	}
 
 	mergedData, err := yaml.Marshal(&c)
@@ -107,17 +161,83 @@ func (k K3s) Images(ctx context.Context) (map[name.Reference]v1.Image, error) {
 	return images.ResolveRemoteRefs(imgs...)
 }
 
+// arch returns k.Arch, defaulting to amd64
+func (k K3s) arch() string {
+	if k.Arch == "" {
+		return "amd64"
+	}
+	return k.Arch
+}
+
+// binaryName returns the release asset name for k's arch, e.g. k3s,
+// k3s-arm64, k3s-armhf, or k3s-s390x
+func (k K3s) binaryName() string {
+	switch k.arch() {
+	case "arm64":
+		return "k3s-arm64"
+	case "arm":
+		return "k3s-armhf"
+	case "s390x":
+		return "k3s-s390x"
+	default:
+		return "k3s"
+	}
+}
+
+// checksumFile returns the sha256sum manifest asset name for k's arch
+func (k K3s) checksumFile() string {
+	switch k.arch() {
+	case "arm64":
+		return "sha256sum-arm64.txt"
+	case "arm":
+		return "sha256sum-arm.txt"
+	case "s390x":
+		return "sha256sum-s390x.txt"
+	default:
+		return "sha256sum-amd64.txt"
+	}
+}
+
+// checksum fetches and caches k's sha256sum manifest and returns the
+// expected hash of binName
+func (k K3s) checksum(binName string) (string, error) {
+	manifest := k.checksumFile()
+	u := fmt.Sprintf("%s/%s/%s", k3sReleaseUrl, k.Version, manifest)
+
+	// Cache filename must include k.Version: manifest names like
+	// sha256sum-amd64.txt are shared across releases, so without the
+	// version a cached copy from an older release would silently apply to
+	// a newer one's binary and fail checksum verification.
+	fname := fmt.Sprintf("%s-%s", k.Version, manifest)
+	data, err := fetchCachedText(k.DataPath("cache", "checksums"), u, fname)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checksums for k3s %s: %w", k.Version, err)
+	}
+
+	sum, ok := parseSHA256SumFile(data)[binName]
+	if !ok {
+		return "", fmt.Errorf("no checksum for %s in %s", binName, u)
+	}
+	return sum, nil
+}
+
+// Binary returns a reader over k's release binary, verified against its
+// published sha256sum and served from a content-addressed local cache under
+// DataPath("cache/bin") after the first fetch
 func (k K3s) Binary() (io.ReadCloser, error) {
-	u, err := url.Parse(fmt.Sprintf("%s/%s/%s", k3sReleaseUrl, k.Version, k.Name()))
+	binName := k.binaryName()
+
+	sum, err := k.checksum(binName)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.Get(u.String())
-	if err != nil || resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to return executable for k3s %s from %s", k.Version, u.String())
+	u := fmt.Sprintf("%s/%s/%s", k3sReleaseUrl, k.Version, binName)
+	path, err := fetchToCache(k.DataPath("cache", "bin"), u, sum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to return executable for k3s %s from %s: %w", k.Version, u, err)
 	}
-	return resp.Body, nil
+	return os.Open(path)
 }
 
 //SystemObjects returns a slice of object.ObjMetadata required for driver to be functional and accept new resources
@@ -131,40 +251,49 @@ func (k K3s) SystemObjects() (objs []object.ObjMetadata) {
 }
 
 func (k K3s) Start(out io.Writer) error {
-	if err := os.WriteFile("/opt/hauler/bin/k3s-init.sh", []byte(k3sInit), 0755); err != nil {
-		return err
+	installer := k.Installer
+	if installer == nil {
+		installer = ScriptInstaller{}
 	}
 
-	cmd := exec.Command("/bin/sh", "/opt/hauler/bin/k3s-init.sh")
-
-	cmd.Env = append(os.Environ(), []string{
-		"INSTALL_K3S_SKIP_DOWNLOAD=true",
-		"INSTALL_K3S_SELINUX_WARN=true",
-		"INSTALL_K3S_SKIP_SELINUX_RPM=true",
-		"INSTALL_K3S_BIN_DIR=/opt/hauler/bin",
+	spec := InstallSpec{
+		Name:      k.Name(),
+		BinDir:    "/opt/hauler/bin",
+		ImagesDir: k.DataPath("agent", "images"),
+		Script:    k3sInit,
+		Env: []string{
+			"INSTALL_K3S_SKIP_DOWNLOAD=true",
+			"INSTALL_K3S_SELINUX_WARN=true",
+			"INSTALL_K3S_SKIP_SELINUX_RPM=true",
+			"INSTALL_K3S_BIN_DIR=/opt/hauler/bin",
+		},
+	}
 
-		//TODO: Provide a real dryrun option
-		//"INSTALL_K3S_SKIP_START=true",
-	}...)
+	// k.Config.ExtraArgs are already written into config.yaml by WriteConfig,
+	// which k3s reads on every start; passing them again here via
+	// INSTALL_K3S_EXEC would just double-specify the same flags.
+	return installer.Install(spec, out)
+}
 
-	cmd.Stdout = out
-	return cmd.Run()
+// Probe waits for k's SystemObjects to become Ready and reports a
+// structured diagnostic summary of the running cluster
+func (k K3s) Probe(ctx context.Context) (*ProbeResult, error) {
+	return probe(ctx, k, defaultProbeTimeout)
 }
 
+// listImages returns k3s's airgap image manifest, cached under
+// DataPath("cache/images") so repeat/airgap builds don't need network access
 func (k K3s) listImages() ([]string, error) {
-	u, err := url.Parse(fmt.Sprintf("%s/%s/k3s-images.txt", k3sReleaseUrl, k.Version))
-	if err != nil {
-		return nil, err
-	}
+	fname := fmt.Sprintf("k3s-images-%s.txt", k.Version)
+	u := fmt.Sprintf("%s/%s/k3s-images.txt", k3sReleaseUrl, k.Version)
 
-	resp, err := http.Get(u.String())
-	if err != nil || resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to return images for k3s %s from %s", k.Version, u.String())
+	data, err := fetchCachedText(k.DataPath("cache", "images"), u, fname)
+	if err != nil {
+		return nil, fmt.Errorf("failed to return images for k3s %s from %s: %w", k.Version, u, err)
 	}
-	defer resp.Body.Close()
 
 	var imgs []string
-	scanner := bufio.NewScanner(resp.Body)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
 	for scanner.Scan() {
 		imgs = append(imgs, scanner.Text())
 	}
@@ -0,0 +1,47 @@
+package driver
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+type fakeInstaller struct {
+	spec InstallSpec
+}
+
+func (f *fakeInstaller) Install(spec InstallSpec, out io.Writer) error {
+	f.spec = spec
+	return nil
+}
+
+func TestK3sStartUsesInjectedInstaller(t *testing.T) {
+	fake := &fakeInstaller{}
+	k := NewK3s()
+	k.Installer = fake
+
+	if err := k.Start(io.Discard); err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+
+	if fake.spec.Name != "k3s" {
+		t.Errorf("spec.Name = %q, want %q", fake.spec.Name, "k3s")
+	}
+	if fake.spec.Script == "" {
+		t.Error("spec.Script is empty, want embedded k3s-init.sh contents")
+	}
+}
+
+func TestK3sStartDryRun(t *testing.T) {
+	k := NewK3s()
+	k.Installer = DryRunInstaller{}
+
+	var out strings.Builder
+	if err := k.Start(&out); err != nil {
+		t.Fatalf("Start() returned error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "[dryrun]") {
+		t.Errorf("Start() output = %q, want it to contain a dryrun plan", out.String())
+	}
+}
@@ -0,0 +1,206 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/cli-utils/pkg/object"
+)
+
+// defaultProbeTimeout bounds how long Probe waits for SystemObjects to
+// become Ready before giving up and reporting a diagnostic result
+const defaultProbeTimeout = 2 * time.Minute
+
+// knownCNIs are matched, in order, against kube-system daemonset names to
+// identify the cluster's CNI
+var knownCNIs = []string{"flannel", "calico", "cilium", "canal", "weave"}
+
+// ProbeResult is a structured snapshot of a driver's post-Start health,
+// modeled on kubearmor-client's post-install probe: enough detail that an
+// operator can tell at a glance whether the air-gap bootstrap succeeded
+// end-to-end, or exactly which component didn't come up.
+type ProbeResult struct {
+	Driver string
+	Ready  bool
+
+	NodeStatus       string
+	KubeletVersion   string
+	ContainerRuntime string
+	CNI              string
+
+	// PodCounts maps namespace to its count of Running pods
+	PodCounts map[string]int
+
+	// ImagesPresent/ImagesMissing split the driver's shipped image set by
+	// whether containerd's image store on the node reports having them
+	ImagesPresent []string
+	ImagesMissing []string
+}
+
+// Summary renders a one-line, human readable verdict for ProbeResult
+func (p *ProbeResult) Summary() string {
+	if p.Ready {
+		return fmt.Sprintf("%s is ready: node=%s kubelet=%s runtime=%s cni=%s images=%d/%d present",
+			p.Driver, p.NodeStatus, p.KubeletVersion, p.ContainerRuntime, p.CNI,
+			len(p.ImagesPresent), len(p.ImagesPresent)+len(p.ImagesMissing))
+	}
+	return fmt.Sprintf("%s is not ready: node=%s kubelet=%s runtime=%s cni=%s missing images=%v",
+		p.Driver, p.NodeStatus, p.KubeletVersion, p.ContainerRuntime, p.CNI, p.ImagesMissing)
+}
+
+// probe connects to d using its KubeConfigPath, waits for d's SystemObjects
+// to reach Ready (or ctx/timeout to elapse), and reports back a ProbeResult.
+// It's shared by every Driver implementation's Probe method.
+func probe(ctx context.Context, d Driver, timeout time.Duration) (*ProbeResult, error) {
+	result := &ProbeResult{Driver: d.Name(), PodCounts: map[string]int{}}
+
+	cfg, err := clientcmd.BuildConfigFromFlags("", d.KubeConfigPath())
+	if err != nil {
+		return result, fmt.Errorf("building kubeconfig: %w", err)
+	}
+
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return result, fmt.Errorf("building client: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, obj := range d.SystemObjects() {
+		if err := waitForReady(ctx, cs, obj, deadline); err != nil {
+			return result, fmt.Errorf("waiting for %s/%s to become ready: %w", obj.Namespace, obj.Name, err)
+		}
+	}
+
+	nodes, err := cs.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("listing nodes: %w", err)
+	}
+
+	haveImages := map[string]bool{}
+	for _, n := range nodes.Items {
+		for _, cond := range n.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				result.NodeStatus = string(cond.Status)
+			}
+		}
+		result.KubeletVersion = n.Status.NodeInfo.KubeletVersion
+		result.ContainerRuntime = n.Status.NodeInfo.ContainerRuntimeVersion
+
+		for _, img := range n.Status.Images {
+			for _, tag := range img.Names {
+				haveImages[tag] = true
+			}
+		}
+	}
+
+	daemonsets, err := cs.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("listing kube-system daemonsets: %w", err)
+	}
+	result.CNI = "unknown"
+findCNI:
+	for _, cni := range knownCNIs {
+		for _, ds := range daemonsets.Items {
+			if strings.Contains(ds.Name, cni) {
+				result.CNI = cni
+				break findCNI
+			}
+		}
+	}
+
+	pods, err := cs.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, fmt.Errorf("listing pods: %w", err)
+	}
+	for _, p := range pods.Items {
+		if p.Status.Phase == corev1.PodRunning {
+			result.PodCounts[p.Namespace]++
+		}
+	}
+
+	imgs, err := d.Images(ctx)
+	if err != nil {
+		return result, fmt.Errorf("resolving shipped images: %w", err)
+	}
+
+	have := make(map[string]bool, len(haveImages))
+	for tag := range haveImages {
+		have[normalizeImageRef(tag)] = true
+	}
+	for ref := range imgs {
+		if have[normalizeImageRef(ref.String())] {
+			result.ImagesPresent = append(result.ImagesPresent, ref.String())
+		} else {
+			result.ImagesMissing = append(result.ImagesMissing, ref.String())
+		}
+	}
+
+	// Image presence is diagnostic, not gating: node image-store reporting
+	// lags the registry/repository forms resolved refs come back as (e.g.
+	// the digest-pinned form containerd reports vs. the tag form a ref
+	// resolves to), so treating a mismatch as "not ready" produces false
+	// negatives on otherwise healthy nodes.
+	result.Ready = result.NodeStatus == string(corev1.ConditionTrue)
+	return result, nil
+}
+
+// normalizeImageRef strips the registry host and any tag/digest suffix so
+// images reported by containerd's image store (often digest-pinned, and
+// sometimes under a different registry alias such as docker.io vs.
+// index.docker.io) can be compared against hauler's resolved refs by
+// repository path alone.
+func normalizeImageRef(ref string) string {
+	if i := strings.IndexAny(ref, "/"); i >= 0 {
+		host := ref[:i]
+		if strings.Contains(host, ".") || strings.Contains(host, ":") || host == "localhost" {
+			ref = ref[i+1:]
+		}
+	}
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndexByte(ref, ':'); i >= 0 && i > strings.LastIndexByte(ref, '/') {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// waitForReady blocks until obj reports Ready, ctx is cancelled, or deadline
+// passes
+func waitForReady(ctx context.Context, cs *kubernetes.Clientset, obj object.ObjMetadata, deadline time.Time) error {
+	for {
+		ready, err := isReady(ctx, cs, obj)
+		if err == nil && ready {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for readiness")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func isReady(ctx context.Context, cs *kubernetes.Clientset, obj object.ObjMetadata) (bool, error) {
+	switch obj.GroupKind.Kind {
+	case "Deployment":
+		d, err := cs.AppsV1().Deployments(obj.Namespace).Get(ctx, obj.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return d.Spec.Replicas != nil && d.Status.ReadyReplicas >= *d.Spec.Replicas, nil
+	default:
+		return false, fmt.Errorf("unsupported system object kind %q", obj.GroupKind.Kind)
+	}
+}
@@ -0,0 +1,186 @@
+package driver
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/imdario/mergo"
+	"github.com/rancherfederal/hauler/pkg/packager/images"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/cli-utils/pkg/object"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	rke2ReleaseUrl = "https://github.com/rancher/rke2/releases/download"
+)
+
+//go:embed embed/rke2-init.sh
+var rke2Init string
+
+// RKE2 is the Driver implementation for rancher/rke2
+type RKE2 struct {
+	Version string
+	Arch    string
+
+	Config RKE2Config
+}
+
+var _ Driver = RKE2{}
+
+//TODO: Would be nice if these just pointed to rke2/pkg/cli/cmds
+type RKE2Config struct {
+	DataDir        string `json:"data-dir,omitempty"`
+	KubeConfig     string `json:"write-kubeconfig,omitempty"`
+	KubeConfigMode string `json:"write-kubeconfig-mode,omitempty"`
+
+	Disable []string `json:"disable,omitempty"`
+}
+
+//NewRKE2 returns a new rke2 driver
+func NewRKE2() RKE2 {
+	//TODO: Allow for configuration overrides
+	return RKE2{
+		Arch: runtime.GOARCH,
+		Config: RKE2Config{
+			DataDir:        "/var/lib/rancher/rke2",
+			KubeConfig:     "/etc/rancher/rke2/rke2.yaml",
+			KubeConfigMode: "0644",
+			Disable:        []string{},
+		},
+	}
+}
+
+func (r RKE2) Name() string { return "rke2" }
+
+func (r RKE2) KubeConfigPath() string { return r.Config.KubeConfig }
+
+func (r RKE2) DataPath(elem ...string) string {
+	base := []string{r.Config.DataDir}
+	return filepath.Join(append(base, elem...)...)
+}
+
+func (r RKE2) WriteConfig() error {
+	kCfgPath := filepath.Dir(r.Config.KubeConfig)
+	if err := os.MkdirAll(kCfgPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(r.Config)
+	if err != nil {
+		return err
+	}
+
+	c := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return err
+	}
+
+	var uc map[string]interface{}
+	path := filepath.Join(kCfgPath, "config.yaml")
+	if data, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(data, &uc); err != nil {
+			return err
+		}
+	}
+
+	//Merge with user defined configs taking precedence
+	if err := mergo.Merge(&c, uc, mergo.WithOverride); err != nil {
+		return err
+	}
+
+	mergedData, err := yaml.Marshal(&c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, mergedData, 0644)
+}
+
+func (r RKE2) Images(ctx context.Context) (map[name.Reference]v1.Image, error) {
+	imgs, err := r.listImages()
+	if err != nil {
+		return nil, err
+	}
+	return images.ResolveRemoteRefs(imgs...)
+}
+
+// Binary returns the rke2 release tarball for r.Arch (e.g. rke2.linux-amd64.tar.gz)
+func (r RKE2) Binary() (io.ReadCloser, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/%s/rke2.linux-%s.tar.gz", rke2ReleaseUrl, r.Version, r.Arch))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(u.String())
+	if err != nil || resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to return executable for rke2 %s from %s", r.Version, u.String())
+	}
+	return resp.Body, nil
+}
+
+//SystemObjects returns a slice of object.ObjMetadata required for driver to be functional and accept new resources
+//hauler's bootstrapping sequence will always wait for SystemObjects to be in a Ready status before proceeding
+func (r RKE2) SystemObjects() (objs []object.ObjMetadata) {
+	for _, dep := range []string{"rke2-coredns-rke2-coredns"} {
+		objMeta, _ := object.CreateObjMetadata("kube-system", dep, schema.GroupKind{Kind: "Deployment", Group: "apps"})
+		objs = append(objs, objMeta)
+	}
+	return objs
+}
+
+func (r RKE2) Start(out io.Writer) error {
+	if err := os.WriteFile("/opt/hauler/bin/rke2-init.sh", []byte(rke2Init), 0755); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("/bin/sh", "/opt/hauler/bin/rke2-init.sh")
+
+	cmd.Env = append(os.Environ(), []string{
+		"INSTALL_RKE2_SKIP_DOWNLOAD=true",
+		"INSTALL_RKE2_ARTIFACT_PATH=/opt/hauler/bin",
+		"INSTALL_RKE2_TYPE=server",
+	}...)
+
+	cmd.Stdout = out
+	return cmd.Run()
+}
+
+// Probe waits for r's SystemObjects to become Ready and reports a
+// structured diagnostic summary of the running cluster
+func (r RKE2) Probe(ctx context.Context) (*ProbeResult, error) {
+	return probe(ctx, r, defaultProbeTimeout)
+}
+
+// listImages fetches rke2's air-gap image manifest, e.g. rke2-images.linux-amd64.txt
+func (r RKE2) listImages() ([]string, error) {
+	u, err := url.Parse(fmt.Sprintf("%s/%s/rke2-images.linux-%s.txt", rke2ReleaseUrl, r.Version, r.Arch))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(u.String())
+	if err != nil || resp.StatusCode != 200 {
+		return nil, fmt.Errorf("failed to return images for rke2 %s from %s", r.Version, u.String())
+	}
+	defer resp.Body.Close()
+
+	var imgs []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		imgs = append(imgs, scanner.Text())
+	}
+
+	return imgs, nil
+}